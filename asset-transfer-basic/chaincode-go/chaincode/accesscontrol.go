@@ -0,0 +1,90 @@
+package chaincode
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-chaincode-go/v2/pkg/cid"
+	"github.com/hyperledger/fabric-contract-api-go/v2/contractapi"
+)
+
+// distribAdminAttr/distribAdminValue identify the Distrib admin either via a
+// cert attribute (role=distrib-admin) or, failing that, via its MSP.
+const (
+	distribAdminAttr  = "role"
+	distribAdminValue = "distrib-admin"
+	distribAdminMSP   = "Org1MSP"
+)
+
+// Authorizer abstracts the client-identity checks SmartContract relies on so
+// that unit tests can inject a fake identity instead of a real MSP certificate.
+type Authorizer interface {
+	// CallerID returns the stable identifier (the certificate's CN) of the invoking identity.
+	CallerID(ctx contractapi.TransactionContextInterface) (string, error)
+	// IsDistribAdmin reports whether the invoking identity may act on Distrib's behalf.
+	IsDistribAdmin(ctx contractapi.TransactionContextInterface) (bool, error)
+}
+
+// cidAuthorizer is the production Authorizer, backed by the fabric-chaincode-go cid package.
+type cidAuthorizer struct{}
+
+func (cidAuthorizer) CallerID(ctx contractapi.TransactionContextInterface) (string, error) {
+	cert, err := cid.GetX509Certificate(ctx.GetStub())
+	if err != nil {
+		return "", fmt.Errorf("failed to read caller certificate: %v", err)
+	}
+
+	return cert.Subject.CommonName, nil
+}
+
+func (cidAuthorizer) IsDistribAdmin(ctx contractapi.TransactionContextInterface) (bool, error) {
+	value, ok, err := cid.GetAttributeValue(ctx.GetStub(), distribAdminAttr)
+	if err != nil {
+		return false, fmt.Errorf("failed to read caller attributes: %v", err)
+	}
+	if ok && value == distribAdminValue {
+		return true, nil
+	}
+
+	mspID, err := cid.GetMSPID(ctx.GetStub())
+	if err != nil {
+		return false, fmt.Errorf("failed to read caller MSP ID: %v", err)
+	}
+
+	return mspID == distribAdminMSP, nil
+}
+
+// authorizer returns s.Authorizer if one was injected (e.g. by a test), otherwise
+// the production cid-backed Authorizer.
+func (s *SmartContract) authorizer() Authorizer {
+	if s.Authorizer != nil {
+		return s.Authorizer
+	}
+
+	return cidAuthorizer{}
+}
+
+// requireDistribAdmin returns an error unless the invoking identity is authorized to act as Distrib.
+func (s *SmartContract) requireDistribAdmin(ctx contractapi.TransactionContextInterface) error {
+	isAdmin, err := s.authorizer().IsDistribAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	if !isAdmin {
+		return fmt.Errorf("caller is not authorized to act as the Distrib admin")
+	}
+
+	return nil
+}
+
+// requireCaller returns an error unless the invoking identity's CN matches owner.
+func (s *SmartContract) requireCaller(ctx contractapi.TransactionContextInterface, owner string) error {
+	callerID, err := s.authorizer().CallerID(ctx)
+	if err != nil {
+		return err
+	}
+	if callerID != owner {
+		return fmt.Errorf("identity %s is not authorized to act on behalf of %s", callerID, owner)
+	}
+
+	return nil
+}
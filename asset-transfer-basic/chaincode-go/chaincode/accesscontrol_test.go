@@ -0,0 +1,75 @@
+package chaincode
+
+import "testing"
+
+func TestRequireCallerAllowsMatchingIdentity(t *testing.T) {
+	ctx, _ := newFakeContext()
+	contract := &SmartContract{Authorizer: &fakeAuthorizer{callerID: "Team1"}}
+
+	if err := contract.requireCaller(ctx, "Team1"); err != nil {
+		t.Fatalf("expected caller matching owner to be allowed, got error: %v", err)
+	}
+}
+
+func TestRequireCallerRejectsMismatchedIdentity(t *testing.T) {
+	ctx, _ := newFakeContext()
+	contract := &SmartContract{Authorizer: &fakeAuthorizer{callerID: "Team2"}}
+
+	if err := contract.requireCaller(ctx, "Team1"); err == nil {
+		t.Fatal("expected an error when the caller does not match owner")
+	}
+}
+
+func TestRequireDistribAdminAllowsAdmin(t *testing.T) {
+	ctx, _ := newFakeContext()
+	contract := &SmartContract{Authorizer: &fakeAuthorizer{isAdmin: true}}
+
+	if err := contract.requireDistribAdmin(ctx); err != nil {
+		t.Fatalf("expected the Distrib admin identity to be allowed, got error: %v", err)
+	}
+}
+
+func TestRequireDistribAdminRejectsNonAdmin(t *testing.T) {
+	ctx, _ := newFakeContext()
+	contract := &SmartContract{Authorizer: &fakeAuthorizer{isAdmin: false}}
+
+	if err := contract.requireDistribAdmin(ctx); err == nil {
+		t.Fatal("expected an error when the caller is not the Distrib admin")
+	}
+}
+
+func TestCreateAssetRejectsCallerOtherThanOwner(t *testing.T) {
+	ctx, _ := newFakeContext()
+	contract := &SmartContract{Authorizer: &fakeAuthorizer{callerID: "Mallory"}}
+
+	if err := contract.CreateAsset(ctx, "gem", 10, "Team1"); err == nil {
+		t.Fatal("expected CreateAsset to reject a caller impersonating a different owner")
+	}
+}
+
+func TestUpdateAssetRejectsNonOwnerCaller(t *testing.T) {
+	ctx, stub := newFakeContext()
+	contract := &SmartContract{Authorizer: &fakeAuthorizer{callerID: "Team1"}}
+	if err := contract.CreateAsset(ctx, "gem", 10, "Team1"); err != nil {
+		t.Fatalf("setup CreateAsset failed: %v", err)
+	}
+
+	contract.Authorizer = &fakeAuthorizer{callerID: "Mallory"}
+	if err := contract.UpdateAsset(ctx, "gem", "Team1", 99); err == nil {
+		t.Fatal("expected UpdateAsset to reject a caller that is not the current owner")
+	}
+
+	key, _ := stub.CreateCompositeKey("Asset", []string{"gem", "Team1"})
+	if string(stub.state[key]) == `{"ID":"gem","Owner":"Team1","Amount":99}` {
+		t.Fatal("asset was updated despite the caller not being the owner")
+	}
+}
+
+func TestInitLedgerRejectsNonDistribAdmin(t *testing.T) {
+	ctx, _ := newFakeContext()
+	contract := &SmartContract{Authorizer: &fakeAuthorizer{isAdmin: false}}
+
+	if err := contract.InitLedger(ctx); err == nil {
+		t.Fatal("expected InitLedger to reject a non-admin caller")
+	}
+}
@@ -0,0 +1,58 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/v2/contractapi"
+)
+
+// AssetCreated is emitted whenever CreateAsset commits a new asset to the ledger.
+type AssetCreated struct {
+	ID     string `json:"ID"`
+	Owner  string `json:"Owner"`
+	Amount int    `json:"Amount"`
+	TxID   string `json:"TxID"`
+}
+
+// AssetUpdated is emitted whenever UpdateAsset overwrites an asset's amount.
+type AssetUpdated struct {
+	ID     string `json:"ID"`
+	Owner  string `json:"Owner"`
+	Amount int    `json:"Amount"`
+	TxID   string `json:"TxID"`
+}
+
+// AssetTransferred is emitted whenever TransferAsset moves units of an asset between owners.
+type AssetTransferred struct {
+	ID       string `json:"ID"`
+	OldOwner string `json:"OldOwner"`
+	NewOwner string `json:"NewOwner"`
+	Amount   int    `json:"Amount"`
+	TxID     string `json:"TxID"`
+}
+
+// GemExchangedForExp is emitted whenever TransferGemToDistrib credits a user's Exp balance.
+// Client apps subscribe to this event via the Fabric Gateway event service to drive
+// auditable Gem/Exp flows.
+type GemExchangedForExp struct {
+	User        string `json:"User"`
+	GemAmount   int    `json:"GemAmount"`
+	ExpCredited int    `json:"ExpCredited"`
+	TxID        string `json:"TxID"`
+	Timestamp   int64  `json:"Timestamp"`
+}
+
+// emitEvent marshals payload and sets it as a chaincode event under name.
+func emitEvent(ctx contractapi.TransactionContextInterface, name string, payload interface{}) error {
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s event payload: %v", name, err)
+	}
+
+	if err := ctx.GetStub().SetEvent(name, payloadJSON); err != nil {
+		return fmt.Errorf("failed to emit %s event: %v", name, err)
+	}
+
+	return nil
+}
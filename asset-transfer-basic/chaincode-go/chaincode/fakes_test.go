@@ -0,0 +1,334 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hyperledger/fabric-chaincode-go/v2/shim"
+	"github.com/hyperledger/fabric-contract-api-go/v2/contractapi"
+	"github.com/hyperledger/fabric-protos-go-apiv2/ledger/queryresult"
+	"github.com/hyperledger/fabric-protos-go-apiv2/peer"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// fabric-chaincode-go/v2 doesn't ship a shimtest MockStub, so these are small
+// hand-rolled fakes covering exactly the ChaincodeStubInterface surface the
+// chaincode package exercises.
+
+const fakeCompositeKeyNamespace = "\x00"
+
+// fakeStub is an in-memory ChaincodeStubInterface good enough to exercise
+// PutState/GetState/DelState, composite keys, range scans and events.
+// Embedding the real interface means any method we don't override panics if
+// a test happens to exercise it, which is exactly the failure mode we want.
+type fakeStub struct {
+	shim.ChaincodeStubInterface
+
+	state       map[string][]byte
+	history     map[string][]*queryresult.KeyModification
+	historySeq  int64
+	txID        string
+	timestamp   *timestamppb.Timestamp
+	lastEvent   string
+	lastPayload []byte
+}
+
+func newFakeStub() *fakeStub {
+	return &fakeStub{
+		state:     map[string][]byte{},
+		history:   map[string][]*queryresult.KeyModification{},
+		txID:      "tx1",
+		timestamp: &timestamppb.Timestamp{Seconds: 1700000000},
+	}
+}
+
+func (f *fakeStub) GetState(key string) ([]byte, error) {
+	return f.state[key], nil
+}
+
+func (f *fakeStub) PutState(key string, value []byte) error {
+	f.state[key] = value
+	f.recordHistory(key, value, false)
+	return nil
+}
+
+func (f *fakeStub) DelState(key string) error {
+	delete(f.state, key)
+	f.recordHistory(key, nil, true)
+	return nil
+}
+
+// recordHistory prepends a KeyModification for key, so f.history[key] comes out
+// newest-first, matching the real GetHistoryForKey's documented ordering.
+func (f *fakeStub) recordHistory(key string, value []byte, isDelete bool) {
+	f.historySeq++
+	modification := &queryresult.KeyModification{
+		TxId:      fmt.Sprintf("history-tx-%d", f.historySeq),
+		Value:     value,
+		Timestamp: &timestamppb.Timestamp{Seconds: f.timestamp.Seconds + f.historySeq},
+		IsDelete:  isDelete,
+	}
+	f.history[key] = append([]*queryresult.KeyModification{modification}, f.history[key]...)
+}
+
+func (f *fakeStub) CreateCompositeKey(objectType string, attributes []string) (string, error) {
+	key := fakeCompositeKeyNamespace + objectType + fakeCompositeKeyNamespace
+	for _, attr := range attributes {
+		key += attr + fakeCompositeKeyNamespace
+	}
+
+	return key, nil
+}
+
+func (f *fakeStub) SplitCompositeKey(compositeKey string) (string, []string, error) {
+	if !strings.HasPrefix(compositeKey, fakeCompositeKeyNamespace) {
+		return "", nil, fmt.Errorf("key %q is not a composite key", compositeKey)
+	}
+
+	segments := strings.Split(strings.TrimPrefix(compositeKey, fakeCompositeKeyNamespace), fakeCompositeKeyNamespace)
+	if len(segments) < 2 {
+		return "", nil, fmt.Errorf("malformed composite key %q", compositeKey)
+	}
+
+	return segments[0], segments[1 : len(segments)-1], nil
+}
+
+func (f *fakeStub) GetStateByRange(startKey string, endKey string) (shim.StateQueryIteratorInterface, error) {
+	var keys []string
+	for key := range f.state {
+		if key < startKey {
+			continue
+		}
+		if endKey != "" && key >= endKey {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return &fakeIterator{stub: f, keys: keys}, nil
+}
+
+func (f *fakeStub) GetStateByPartialCompositeKey(objectType string, attributes []string) (shim.StateQueryIteratorInterface, error) {
+	prefix, err := f.CreateCompositeKey(objectType, attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for key := range f.state {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	return &fakeIterator{stub: f, keys: keys}, nil
+}
+
+func (f *fakeStub) GetTxID() string {
+	return f.txID
+}
+
+func (f *fakeStub) GetTxTimestamp() (*timestamppb.Timestamp, error) {
+	return f.timestamp, nil
+}
+
+func (f *fakeStub) SetEvent(name string, payload []byte) error {
+	f.lastEvent = name
+	f.lastPayload = payload
+	return nil
+}
+
+func (f *fakeStub) GetHistoryForKey(key string) (shim.HistoryQueryIteratorInterface, error) {
+	return &fakeHistoryIterator{modifications: f.history[key]}, nil
+}
+
+func (f *fakeStub) GetQueryResult(query string) (shim.StateQueryIteratorInterface, error) {
+	keys, err := f.matchSelector(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fakeIterator{stub: f, keys: keys}, nil
+}
+
+func (f *fakeStub) GetQueryResultWithPagination(query string, pageSize int32, bookmark string) (shim.StateQueryIteratorInterface, *peer.QueryResponseMetadata, error) {
+	keys, err := f.matchSelector(query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return f.paginate(keys, pageSize, bookmark)
+}
+
+func (f *fakeStub) GetStateByPartialCompositeKeyWithPagination(objectType string, attributes []string, pageSize int32, bookmark string) (shim.StateQueryIteratorInterface, *peer.QueryResponseMetadata, error) {
+	prefix, err := f.CreateCompositeKey(objectType, attributes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var keys []string
+	for key := range f.state {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	return f.paginate(keys, pageSize, bookmark)
+}
+
+// matchSelector naively evaluates a Mango `{"selector": {...}}` query by requiring
+// every field in selector to equal the matching field of the stored JSON document.
+// It only needs to support the plain field-equality selectors marshalSelector builds.
+func (f *fakeStub) matchSelector(query string) ([]string, error) {
+	var parsed struct {
+		Selector map[string]interface{} `json:"selector"`
+	}
+	if err := json.Unmarshal([]byte(query), &parsed); err != nil {
+		return nil, fmt.Errorf("invalid query %q: %v", query, err)
+	}
+
+	var keys []string
+	for key, value := range f.state {
+		var doc map[string]interface{}
+		if err := json.Unmarshal(value, &doc); err != nil {
+			continue
+		}
+
+		matches := true
+		for field, want := range parsed.Selector {
+			if fmt.Sprintf("%v", doc[field]) != fmt.Sprintf("%v", want) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys, nil
+}
+
+// paginate slices keys into a page of at most pageSize entries starting at bookmark,
+// an opaque decimal offset, and returns the bookmark a caller must pass back in to
+// resume from where this page left off (empty once the last page has been served).
+func (f *fakeStub) paginate(keys []string, pageSize int32, bookmark string) (shim.StateQueryIteratorInterface, *peer.QueryResponseMetadata, error) {
+	start := 0
+	if bookmark != "" {
+		offset, err := strconv.Atoi(bookmark)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid bookmark %q", bookmark)
+		}
+		start = offset
+	}
+	if start > len(keys) {
+		start = len(keys)
+	}
+
+	end := start + int(pageSize)
+	if end > len(keys) {
+		end = len(keys)
+	}
+	page := keys[start:end]
+
+	nextBookmark := ""
+	if end < len(keys) {
+		nextBookmark = strconv.Itoa(end)
+	}
+
+	return &fakeIterator{stub: f, keys: page}, &peer.QueryResponseMetadata{FetchedRecordsCount: int32(len(page)), Bookmark: nextBookmark}, nil
+}
+
+// fakeIterator walks a fixed, pre-sorted slice of keys against the owning fakeStub's state.
+type fakeIterator struct {
+	stub *fakeStub
+	keys []string
+	pos  int
+}
+
+func (it *fakeIterator) HasNext() bool {
+	return it.pos < len(it.keys)
+}
+
+func (it *fakeIterator) Next() (*queryresult.KV, error) {
+	if !it.HasNext() {
+		return nil, errors.New("no more results")
+	}
+
+	key := it.keys[it.pos]
+	it.pos++
+
+	return &queryresult.KV{Key: key, Value: it.stub.state[key]}, nil
+}
+
+func (it *fakeIterator) Close() error {
+	return nil
+}
+
+// fakeHistoryIterator walks a fixed, newest-first slice of KeyModifications,
+// mirroring GetHistoryForKey's documented ordering.
+type fakeHistoryIterator struct {
+	modifications []*queryresult.KeyModification
+	pos           int
+}
+
+func (it *fakeHistoryIterator) HasNext() bool {
+	return it.pos < len(it.modifications)
+}
+
+func (it *fakeHistoryIterator) Next() (*queryresult.KeyModification, error) {
+	if !it.HasNext() {
+		return nil, errors.New("no more results")
+	}
+
+	modification := it.modifications[it.pos]
+	it.pos++
+
+	return modification, nil
+}
+
+func (it *fakeHistoryIterator) Close() error {
+	return nil
+}
+
+// fakeTransactionContext is a minimal contractapi.TransactionContextInterface that
+// returns a fakeStub from GetStub.
+type fakeTransactionContext struct {
+	contractapi.TransactionContextInterface
+
+	stub *fakeStub
+}
+
+func newFakeContext() (*fakeTransactionContext, *fakeStub) {
+	stub := newFakeStub()
+	return &fakeTransactionContext{stub: stub}, stub
+}
+
+func (f *fakeTransactionContext) GetStub() shim.ChaincodeStubInterface {
+	return f.stub
+}
+
+// fakeAuthorizer is the test double the Authorizer interface seam exists for:
+// it stands in for a real MSP certificate.
+type fakeAuthorizer struct {
+	callerID  string
+	callerErr error
+	isAdmin   bool
+	adminErr  error
+}
+
+func (f *fakeAuthorizer) CallerID(ctx contractapi.TransactionContextInterface) (string, error) {
+	return f.callerID, f.callerErr
+}
+
+func (f *fakeAuthorizer) IsDistribAdmin(ctx contractapi.TransactionContextInterface) (bool, error) {
+	return f.isAdmin, f.adminErr
+}
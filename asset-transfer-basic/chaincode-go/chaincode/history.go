@@ -0,0 +1,100 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hyperledger/fabric-contract-api-go/v2/contractapi"
+)
+
+// HistoryEntry is one historical version of an asset's state, as recorded on the ledger.
+type HistoryEntry struct {
+	TxID      string    `json:"TxID"`
+	Timestamp time.Time `json:"Timestamp"`
+	IsDelete  bool      `json:"IsDelete"`
+	Value     *Asset    `json:"Value"`
+}
+
+// GetAssetHistory returns every historical version of the asset identified by (id, owner), oldest first.
+func (s *SmartContract) GetAssetHistory(ctx contractapi.TransactionContextInterface, id string, owner string) ([]HistoryEntry, error) {
+	compositeKey, err := assetKey(ctx, id, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	return readKeyHistory(ctx, compositeKey)
+}
+
+// GetUserLedger stitches together the history of a user's gem and exp assets, sorted
+// chronologically, into a single auditable timeline of everything that happened to
+// that user's holdings.
+func (s *SmartContract) GetUserLedger(ctx contractapi.TransactionContextInterface, user string) ([]HistoryEntry, error) {
+	gemKey, err := assetKey(ctx, "gem", user)
+	if err != nil {
+		return nil, err
+	}
+	expKey, err := assetKey(ctx, "exp", user)
+	if err != nil {
+		return nil, err
+	}
+
+	gemHistory, err := readKeyHistory(ctx, gemKey)
+	if err != nil {
+		return nil, err
+	}
+	expHistory, err := readKeyHistory(ctx, expKey)
+	if err != nil {
+		return nil, err
+	}
+
+	ledger := append(gemHistory, expHistory...)
+	sort.Slice(ledger, func(i, j int) bool {
+		return ledger[i].Timestamp.Before(ledger[j].Timestamp)
+	})
+
+	return ledger, nil
+}
+
+// readKeyHistory drains GetHistoryForKey for key into HistoryEntry values, oldest first.
+// GetHistoryForKey itself returns newest to oldest, so the entries are sorted by timestamp before return.
+func readKeyHistory(ctx contractapi.TransactionContextInterface, key string) ([]HistoryEntry, error) {
+	historyIterator, err := ctx.GetStub().GetHistoryForKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history for key: %v", err)
+	}
+	defer historyIterator.Close()
+
+	var entries []HistoryEntry
+	for historyIterator.HasNext() {
+		modification, err := historyIterator.Next()
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate over history: %v", err)
+		}
+
+		entry := HistoryEntry{
+			TxID:     modification.TxId,
+			IsDelete: modification.IsDelete,
+		}
+		if modification.Timestamp != nil {
+			entry.Timestamp = time.Unix(modification.Timestamp.Seconds, int64(modification.Timestamp.Nanos)).UTC()
+		}
+
+		if !modification.IsDelete && modification.Value != nil {
+			var asset Asset
+			if err := json.Unmarshal(modification.Value, &asset); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal historical asset value: %v", err)
+			}
+			entry.Value = &asset
+		}
+
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+
+	return entries, nil
+}
@@ -0,0 +1,63 @@
+package chaincode
+
+import "testing"
+
+func TestGetAssetHistoryReturnsOldestFirst(t *testing.T) {
+	contract, ctx, _ := newTestContract("Team1")
+
+	if err := contract.CreateAsset(ctx, "gem", 10, "Team1"); err != nil {
+		t.Fatalf("CreateAsset failed: %v", err)
+	}
+	if err := contract.UpdateAsset(ctx, "gem", "Team1", 20); err != nil {
+		t.Fatalf("UpdateAsset failed: %v", err)
+	}
+	if err := contract.UpdateAsset(ctx, "gem", "Team1", 30); err != nil {
+		t.Fatalf("UpdateAsset failed: %v", err)
+	}
+
+	history, err := contract.GetAssetHistory(ctx, "gem", "Team1")
+	if err != nil {
+		t.Fatalf("GetAssetHistory failed: %v", err)
+	}
+
+	if len(history) != 3 {
+		t.Fatalf("expected 3 historical entries, got %d", len(history))
+	}
+	amounts := []int{history[0].Value.Amount, history[1].Value.Amount, history[2].Value.Amount}
+	if amounts[0] != 10 || amounts[1] != 20 || amounts[2] != 30 {
+		t.Fatalf("expected amounts in oldest-first order [10 20 30], got %v", amounts)
+	}
+	for i := 1; i < len(history); i++ {
+		if history[i].Timestamp.Before(history[i-1].Timestamp) {
+			t.Fatalf("expected entries to be ordered oldest first, entry %d is earlier than entry %d", i, i-1)
+		}
+	}
+}
+
+func TestGetUserLedgerMergesGemAndExpHistoryChronologically(t *testing.T) {
+	contract, ctx, _ := newTestContract("Team1")
+
+	if err := contract.CreateAsset(ctx, "gem", 10, "Team1"); err != nil {
+		t.Fatalf("CreateAsset(gem) failed: %v", err)
+	}
+	if err := contract.CreateAsset(ctx, "exp", 1, "Team1"); err != nil {
+		t.Fatalf("CreateAsset(exp) failed: %v", err)
+	}
+	if err := contract.UpdateAsset(ctx, "gem", "Team1", 20); err != nil {
+		t.Fatalf("UpdateAsset(gem) failed: %v", err)
+	}
+
+	ledger, err := contract.GetUserLedger(ctx, "Team1")
+	if err != nil {
+		t.Fatalf("GetUserLedger failed: %v", err)
+	}
+
+	if len(ledger) != 3 {
+		t.Fatalf("expected 3 entries across both assets, got %d", len(ledger))
+	}
+	for i := 1; i < len(ledger); i++ {
+		if ledger[i].Timestamp.Before(ledger[i-1].Timestamp) {
+			t.Fatalf("expected the merged ledger to be sorted chronologically, entry %d precedes entry %d", i, i-1)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/v2/contractapi"
+)
+
+// policyKey is the single well-known key under which the active minting policy is stored.
+const policyKey = "Policy"
+
+// totalExpMintedKey tracks, as a single counter, how much Exp the ledger has ever minted.
+const totalExpMintedKey = "TotalExpMinted"
+
+// feesOwner/feesAssetID identify the asset that Distrib's transfer fees accrue to.
+const (
+	feesOwner   = "Distrib"
+	feesAssetID = "fees"
+)
+
+// Policy governs how Gem converts to Exp when TransferGemToDistrib runs: the exchange
+// rate (gemPerExpNumerator/gemPerExpDenominator Exp minted per Gem), the fee Distrib keeps,
+// expressed in basis points of the minted Exp, and the supply cap the ledger will
+// refuse to mint past.
+type Policy struct {
+	GemPerExpNumerator   int    `json:"GemPerExpNumerator"`
+	GemPerExpDenominator int    `json:"GemPerExpDenominator"`
+	MaxExpSupply         int    `json:"MaxExpSupply"`
+	DistribFeeBps        int    `json:"DistribFeeBps"`
+	EffectiveFrom        string `json:"EffectiveFrom"`
+}
+
+// PolicyChanged is emitted whenever SetPolicy replaces the active policy.
+type PolicyChanged struct {
+	Policy Policy `json:"Policy"`
+	TxID   string `json:"TxID"`
+}
+
+// defaultPolicy is used until SetPolicy has ever been called: a 1:1 rate, no fee, no cap.
+func defaultPolicy() Policy {
+	return Policy{GemPerExpNumerator: 1, GemPerExpDenominator: 1}
+}
+
+// GetPolicy returns the currently active minting policy, or the default 1:1, no-fee,
+// uncapped policy if SetPolicy has never been invoked.
+func (s *SmartContract) GetPolicy(ctx contractapi.TransactionContextInterface) (*Policy, error) {
+	policyJSON, err := ctx.GetStub().GetState(policyKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy: %v", err)
+	}
+	if policyJSON == nil {
+		policy := defaultPolicy()
+		return &policy, nil
+	}
+
+	var policy Policy
+	if err := json.Unmarshal(policyJSON, &policy); err != nil {
+		return nil, err
+	}
+
+	return &policy, nil
+}
+
+// SetPolicy replaces the active minting policy. Only the Distrib admin identity may invoke it.
+func (s *SmartContract) SetPolicy(ctx contractapi.TransactionContextInterface, gemPerExpNumerator int, gemPerExpDenominator int, maxExpSupply int, distribFeeBps int, effectiveFrom string) error {
+	if err := s.requireDistribAdmin(ctx); err != nil {
+		return err
+	}
+	if gemPerExpNumerator <= 0 || gemPerExpDenominator <= 0 {
+		return fmt.Errorf("gemPerExpNumerator and gemPerExpDenominator must be positive")
+	}
+	if distribFeeBps < 0 || distribFeeBps > 10000 {
+		return fmt.Errorf("distribFeeBps must be between 0 and 10000")
+	}
+	if maxExpSupply < 0 {
+		return fmt.Errorf("maxExpSupply must not be negative")
+	}
+
+	policy := Policy{
+		GemPerExpNumerator:   gemPerExpNumerator,
+		GemPerExpDenominator: gemPerExpDenominator,
+		MaxExpSupply:         maxExpSupply,
+		DistribFeeBps:        distribFeeBps,
+		EffectiveFrom:        effectiveFrom,
+	}
+	policyJSON, err := json.Marshal(policy)
+	if err != nil {
+		return err
+	}
+	if err := ctx.GetStub().PutState(policyKey, policyJSON); err != nil {
+		return fmt.Errorf("failed to store policy: %v", err)
+	}
+
+	return emitEvent(ctx, "PolicyChanged", PolicyChanged{Policy: policy, TxID: ctx.GetStub().GetTxID()})
+}
+
+// totalExpMinted returns the running total of Exp minted across all TransferGemToDistrib calls.
+func totalExpMinted(ctx contractapi.TransactionContextInterface) (int, error) {
+	raw, err := ctx.GetStub().GetState(totalExpMintedKey)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read total Exp minted: %v", err)
+	}
+	if raw == nil {
+		return 0, nil
+	}
+
+	var total int
+	if err := json.Unmarshal(raw, &total); err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// putTotalExpMinted persists the running total of Exp minted.
+func putTotalExpMinted(ctx contractapi.TransactionContextInterface, total int) error {
+	raw, err := json.Marshal(total)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(totalExpMintedKey, raw)
+}
+
+// creditFees adds amount to the fees asset that Distrib's transfer fees accrue to,
+// creating it if this is the first fee ever collected.
+func creditFees(ctx contractapi.TransactionContextInterface, amount int) error {
+	feesKey, err := assetKey(ctx, feesAssetID, feesOwner)
+	if err != nil {
+		return err
+	}
+
+	feesJSON, err := ctx.GetStub().GetState(feesKey)
+	if err != nil {
+		return fmt.Errorf("failed to read fees asset: %v", err)
+	}
+
+	var fees Asset
+	if feesJSON != nil {
+		if err := json.Unmarshal(feesJSON, &fees); err != nil {
+			return fmt.Errorf("failed to unmarshal fees asset: %v", err)
+		}
+	} else {
+		fees = Asset{ID: feesAssetID, Owner: feesOwner, Amount: 0}
+	}
+
+	fees.Amount += amount
+
+	updatedJSON, err := json.Marshal(fees)
+	if err != nil {
+		return err
+	}
+
+	if err := ctx.GetStub().PutState(feesKey, updatedJSON); err != nil {
+		return fmt.Errorf("failed to update fees asset: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestGetPolicyDefaultsToOneToOneNoFeeNoCap(t *testing.T) {
+	contract, ctx, _ := newTestContract("Team1")
+
+	policy, err := contract.GetPolicy(ctx)
+	if err != nil {
+		t.Fatalf("GetPolicy failed: %v", err)
+	}
+	if policy.GemPerExpNumerator != 1 || policy.GemPerExpDenominator != 1 {
+		t.Fatalf("expected default 1:1 rate, got %d:%d", policy.GemPerExpNumerator, policy.GemPerExpDenominator)
+	}
+	if policy.MaxExpSupply != 0 || policy.DistribFeeBps != 0 {
+		t.Fatalf("expected no cap and no fee by default, got %+v", policy)
+	}
+}
+
+func TestSetPolicyRejectsNonDistribAdmin(t *testing.T) {
+	ctx, _ := newFakeContext()
+	contract := &SmartContract{Authorizer: &fakeAuthorizer{isAdmin: false}}
+
+	if err := contract.SetPolicy(ctx, 2, 1, 1000, 500, "2026-01-01"); err == nil {
+		t.Fatal("expected SetPolicy to reject a non-admin caller")
+	}
+}
+
+func TestSetPolicyThenGetPolicyRoundTrips(t *testing.T) {
+	contract, ctx, stub := newTestContract("Distrib")
+
+	if err := contract.SetPolicy(ctx, 2, 1, 1000, 500, "2026-01-01"); err != nil {
+		t.Fatalf("SetPolicy failed: %v", err)
+	}
+
+	policy, err := contract.GetPolicy(ctx)
+	if err != nil {
+		t.Fatalf("GetPolicy failed: %v", err)
+	}
+	if policy.GemPerExpNumerator != 2 || policy.GemPerExpDenominator != 1 {
+		t.Fatalf("expected 2:1 rate, got %d:%d", policy.GemPerExpNumerator, policy.GemPerExpDenominator)
+	}
+	if policy.DistribFeeBps != 500 {
+		t.Fatalf("expected 500 bps fee, got %d", policy.DistribFeeBps)
+	}
+
+	if stub.lastEvent != "PolicyChanged" {
+		t.Fatalf("expected a PolicyChanged event, got %q", stub.lastEvent)
+	}
+	var payload PolicyChanged
+	if err := json.Unmarshal(stub.lastPayload, &payload); err != nil {
+		t.Fatalf("failed to unmarshal PolicyChanged payload: %v", err)
+	}
+	if payload.Policy.DistribFeeBps != 500 {
+		t.Fatalf("expected PolicyChanged payload to report 500 bps fee, got %d", payload.Policy.DistribFeeBps)
+	}
+}
+
+func TestTransferGemToDistribAppliesRateAndFee(t *testing.T) {
+	contract, ctx, stub := newTestContract("Team1")
+	if err := contract.CreateAsset(ctx, "gem", 100, "Team1"); err != nil {
+		t.Fatalf("CreateAsset failed: %v", err)
+	}
+
+	contract.Authorizer = &fakeAuthorizer{callerID: "Distrib", isAdmin: true}
+	// 2 Gem buys 1 Exp, and Distrib keeps a 10% fee on the minted Exp.
+	if err := contract.SetPolicy(ctx, 1, 2, 0, 1000, "2026-01-01"); err != nil {
+		t.Fatalf("SetPolicy failed: %v", err)
+	}
+
+	contract.Authorizer = &fakeAuthorizer{callerID: "Team1"}
+	if err := contract.TransferGemToDistrib(ctx, "Team1", 100); err != nil {
+		t.Fatalf("TransferGemToDistrib failed: %v", err)
+	}
+
+	// grossExp = 100*1/2 = 50, fee = 50*1000/10000 = 5, netExp = 45.
+	userExp, err := contract.ReadAsset(ctx, "exp", "Team1")
+	if err != nil {
+		t.Fatalf("ReadAsset(exp) failed: %v", err)
+	}
+	if userExp.Amount != 45 {
+		t.Fatalf("expected user to be credited 45 Exp, got %d", userExp.Amount)
+	}
+
+	fees, err := contract.ReadAsset(ctx, "fees", "Distrib")
+	if err != nil {
+		t.Fatalf("ReadAsset(fees) failed: %v", err)
+	}
+	if fees.Amount != 5 {
+		t.Fatalf("expected Distrib to have collected 5 Exp in fees, got %d", fees.Amount)
+	}
+
+	if stub.lastEvent != "GemExchangedForExp" {
+		t.Fatalf("expected a GemExchangedForExp event, got %q", stub.lastEvent)
+	}
+	var payload GemExchangedForExp
+	if err := json.Unmarshal(stub.lastPayload, &payload); err != nil {
+		t.Fatalf("failed to unmarshal GemExchangedForExp payload: %v", err)
+	}
+	if payload.User != "Team1" || payload.GemAmount != 100 || payload.ExpCredited != 45 {
+		t.Fatalf("unexpected GemExchangedForExp payload: %+v", payload)
+	}
+}
+
+func TestTransferGemToDistribRejectsAmountTooSmallToMintAnyExp(t *testing.T) {
+	contract, ctx, _ := newTestContract("Team1")
+	if err := contract.CreateAsset(ctx, "gem", 10, "Team1"); err != nil {
+		t.Fatalf("CreateAsset failed: %v", err)
+	}
+
+	contract.Authorizer = &fakeAuthorizer{callerID: "Distrib", isAdmin: true}
+	// 10 Gem per Exp: transferring 1 Gem would truncate to 0 Exp.
+	if err := contract.SetPolicy(ctx, 1, 10, 0, 0, "2026-01-01"); err != nil {
+		t.Fatalf("SetPolicy failed: %v", err)
+	}
+
+	contract.Authorizer = &fakeAuthorizer{callerID: "Team1"}
+	if err := contract.TransferGemToDistrib(ctx, "Team1", 1); err == nil {
+		t.Fatal("expected TransferGemToDistrib to reject a gemAmount that would mint zero Exp")
+	}
+
+	userGem, err := contract.ReadAsset(ctx, "gem", "Team1")
+	if err != nil {
+		t.Fatalf("ReadAsset(gem) failed: %v", err)
+	}
+	if userGem.Amount != 10 {
+		t.Fatalf("expected the rejected transfer to leave the Gem balance untouched, got %d", userGem.Amount)
+	}
+}
+
+func TestTransferGemToDistribRejectsMintOverMaxSupply(t *testing.T) {
+	contract, ctx, _ := newTestContract("Team1")
+	if err := contract.CreateAsset(ctx, "gem", 100, "Team1"); err != nil {
+		t.Fatalf("CreateAsset failed: %v", err)
+	}
+
+	contract.Authorizer = &fakeAuthorizer{callerID: "Distrib", isAdmin: true}
+	if err := contract.SetPolicy(ctx, 1, 1, 50, 0, "2026-01-01"); err != nil {
+		t.Fatalf("SetPolicy failed: %v", err)
+	}
+
+	contract.Authorizer = &fakeAuthorizer{callerID: "Team1"}
+	if err := contract.TransferGemToDistrib(ctx, "Team1", 100); err == nil {
+		t.Fatal("expected TransferGemToDistrib to reject minting past MaxExpSupply")
+	}
+}
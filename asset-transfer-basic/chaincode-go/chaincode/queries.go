@@ -0,0 +1,140 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-chaincode-go/v2/shim"
+	"github.com/hyperledger/fabric-contract-api-go/v2/contractapi"
+)
+
+// PaginatedQueryResult wraps a page of query results together with the
+// bookmark a client must pass back in to fetch the next page.
+type PaginatedQueryResult struct {
+	Assets              []*Asset `json:"assets"`
+	FetchedRecordsCount int32    `json:"fetchedRecordsCount"`
+	Bookmark            string   `json:"bookmark"`
+}
+
+// GetAllAssets returns all assets found in the world state. When pageSize is
+// greater than zero the results are walked page by page via CouchDB
+// pagination and bookmark is used to resume from a previous call; pass an
+// empty bookmark to start from the first page. When pageSize is zero the
+// entire result set is returned in one call, as before.
+func (s *SmartContract) GetAllAssets(ctx contractapi.TransactionContextInterface, pageSize int32, bookmark string) (*PaginatedQueryResult, error) {
+	if pageSize <= 0 {
+		resultsIterator, err := ctx.GetStub().GetStateByPartialCompositeKey("Asset", []string{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get assets: %v", err)
+		}
+		defer resultsIterator.Close()
+
+		assets, err := constructAssetsFromIterator(resultsIterator)
+		if err != nil {
+			return nil, err
+		}
+
+		return &PaginatedQueryResult{Assets: assets, FetchedRecordsCount: int32(len(assets))}, nil
+	}
+
+	resultsIterator, metadata, err := ctx.GetStub().GetStateByPartialCompositeKeyWithPagination("Asset", []string{}, pageSize, bookmark)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assets: %v", err)
+	}
+	defer resultsIterator.Close()
+
+	assets, err := constructAssetsFromIterator(resultsIterator)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PaginatedQueryResult{
+		Assets:              assets,
+		FetchedRecordsCount: metadata.FetchedRecordsCount,
+		Bookmark:            metadata.Bookmark,
+	}, nil
+}
+
+// QueryAssetsByOwner returns all assets, of any id, owned by the given owner.
+// It relies on the indexOwner CouchDB index.
+func (s *SmartContract) QueryAssetsByOwner(ctx contractapi.TransactionContextInterface, owner string) ([]*Asset, error) {
+	queryString, err := marshalSelector(map[string]interface{}{"Owner": owner})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getQueryResultForQueryString(ctx, queryString)
+}
+
+// QueryAssetsByType returns every owner's record for the given asset id (e.g. "gem" or "exp").
+func (s *SmartContract) QueryAssetsByType(ctx contractapi.TransactionContextInterface, id string) ([]*Asset, error) {
+	queryString, err := marshalSelector(map[string]interface{}{"ID": id})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getQueryResultForQueryString(ctx, queryString)
+}
+
+// marshalSelector renders fields as a Mango `{"selector": {...}}` query via json.Marshal,
+// so that caller-supplied values can never break out of their JSON string context.
+func marshalSelector(fields map[string]interface{}) (string, error) {
+	queryJSON, err := json.Marshal(map[string]interface{}{"selector": fields})
+	if err != nil {
+		return "", fmt.Errorf("failed to build query selector: %v", err)
+	}
+
+	return string(queryJSON), nil
+}
+
+// QueryAssets runs an arbitrary CouchDB Mango selector query and walks it one page at a time.
+// selectorJSON must be a JSON object, e.g. `{"selector":{"Owner":"Team1"}}`.
+func (s *SmartContract) QueryAssets(ctx contractapi.TransactionContextInterface, selectorJSON string, pageSize int32, bookmark string) (*PaginatedQueryResult, error) {
+	resultsIterator, metadata, err := ctx.GetStub().GetQueryResultWithPagination(selectorJSON, pageSize, bookmark)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %v", err)
+	}
+	defer resultsIterator.Close()
+
+	assets, err := constructAssetsFromIterator(resultsIterator)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PaginatedQueryResult{
+		Assets:              assets,
+		FetchedRecordsCount: metadata.FetchedRecordsCount,
+		Bookmark:            metadata.Bookmark,
+	}, nil
+}
+
+// getQueryResultForQueryString executes a CouchDB selector query with no pagination
+// and returns every matching asset.
+func (s *SmartContract) getQueryResultForQueryString(ctx contractapi.TransactionContextInterface, queryString string) ([]*Asset, error) {
+	resultsIterator, err := ctx.GetStub().GetQueryResult(queryString)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %v", err)
+	}
+	defer resultsIterator.Close()
+
+	return constructAssetsFromIterator(resultsIterator)
+}
+
+// constructAssetsFromIterator drains a state query iterator into a slice of assets.
+func constructAssetsFromIterator(resultsIterator shim.StateQueryIteratorInterface) ([]*Asset, error) {
+	var assets []*Asset
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate over assets: %v", err)
+		}
+
+		var asset Asset
+		if err := json.Unmarshal(queryResponse.Value, &asset); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal asset: %v", err)
+		}
+		assets = append(assets, &asset)
+	}
+
+	return assets, nil
+}
@@ -0,0 +1,135 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMarshalSelectorEscapesInjectionAttempts(t *testing.T) {
+	malicious := `x", "$or":[{"Owner":{"$ne":"nope"}}], "y":"`
+
+	queryString, err := marshalSelector(map[string]interface{}{"Owner": malicious})
+	if err != nil {
+		t.Fatalf("marshalSelector failed: %v", err)
+	}
+
+	var decoded struct {
+		Selector map[string]interface{} `json:"selector"`
+	}
+	if err := json.Unmarshal([]byte(queryString), &decoded); err != nil {
+		t.Fatalf("expected a single well-formed JSON object, got invalid JSON: %v", err)
+	}
+
+	if len(decoded.Selector) != 1 {
+		t.Fatalf("expected the selector to contain exactly one field, got %v", decoded.Selector)
+	}
+	if decoded.Selector["Owner"] != malicious {
+		t.Fatalf("expected the malicious string to be preserved verbatim as a value, got %v", decoded.Selector["Owner"])
+	}
+}
+
+func TestGetAllAssetsNonPaginatedReturnsEverything(t *testing.T) {
+	contract, ctx, _ := newTestContract("Team1")
+	if err := contract.CreateAsset(ctx, "gem", 10, "Team1"); err != nil {
+		t.Fatalf("CreateAsset failed: %v", err)
+	}
+	if err := contract.CreateAsset(ctx, "exp", 5, "Team1"); err != nil {
+		t.Fatalf("CreateAsset failed: %v", err)
+	}
+	contract.Authorizer = &fakeAuthorizer{callerID: "Team2"}
+	if err := contract.CreateAsset(ctx, "gem", 3, "Team2"); err != nil {
+		t.Fatalf("CreateAsset failed: %v", err)
+	}
+
+	result, err := contract.GetAllAssets(ctx, 0, "")
+	if err != nil {
+		t.Fatalf("GetAllAssets failed: %v", err)
+	}
+	if len(result.Assets) != 3 {
+		t.Fatalf("expected 3 assets, got %d", len(result.Assets))
+	}
+	if result.FetchedRecordsCount != 3 {
+		t.Fatalf("expected FetchedRecordsCount 3, got %d", result.FetchedRecordsCount)
+	}
+	if result.Bookmark != "" {
+		t.Fatalf("expected no bookmark for a non-paginated call, got %q", result.Bookmark)
+	}
+}
+
+func TestGetAllAssetsPaginatesAndResumesFromBookmark(t *testing.T) {
+	contract, ctx, _ := newTestContract("Team1")
+	for _, owner := range []string{"Team1", "Team2", "Team3"} {
+		contract.Authorizer = &fakeAuthorizer{callerID: owner}
+		if err := contract.CreateAsset(ctx, "gem", 1, owner); err != nil {
+			t.Fatalf("CreateAsset failed: %v", err)
+		}
+	}
+
+	firstPage, err := contract.GetAllAssets(ctx, 2, "")
+	if err != nil {
+		t.Fatalf("GetAllAssets (first page) failed: %v", err)
+	}
+	if len(firstPage.Assets) != 2 {
+		t.Fatalf("expected a page of 2 assets, got %d", len(firstPage.Assets))
+	}
+	if firstPage.Bookmark == "" {
+		t.Fatal("expected a non-empty bookmark when more results remain")
+	}
+
+	secondPage, err := contract.GetAllAssets(ctx, 2, firstPage.Bookmark)
+	if err != nil {
+		t.Fatalf("GetAllAssets (second page) failed: %v", err)
+	}
+	if len(secondPage.Assets) != 1 {
+		t.Fatalf("expected the final page to contain the one remaining asset, got %d", len(secondPage.Assets))
+	}
+	if secondPage.Bookmark != "" {
+		t.Fatalf("expected an empty bookmark once all pages are exhausted, got %q", secondPage.Bookmark)
+	}
+}
+
+func TestQueryAssetsByOwnerReturnsOnlyThatOwnersAssets(t *testing.T) {
+	contract, ctx, _ := newTestContract("Team1")
+	if err := contract.CreateAsset(ctx, "gem", 10, "Team1"); err != nil {
+		t.Fatalf("CreateAsset failed: %v", err)
+	}
+	if err := contract.CreateAsset(ctx, "exp", 5, "Team1"); err != nil {
+		t.Fatalf("CreateAsset failed: %v", err)
+	}
+	contract.Authorizer = &fakeAuthorizer{callerID: "Team2"}
+	if err := contract.CreateAsset(ctx, "gem", 3, "Team2"); err != nil {
+		t.Fatalf("CreateAsset failed: %v", err)
+	}
+
+	assets, err := contract.QueryAssetsByOwner(ctx, "Team1")
+	if err != nil {
+		t.Fatalf("QueryAssetsByOwner failed: %v", err)
+	}
+	if len(assets) != 2 {
+		t.Fatalf("expected 2 assets owned by Team1, got %d", len(assets))
+	}
+	for _, asset := range assets {
+		if asset.Owner != "Team1" {
+			t.Fatalf("expected every result to be owned by Team1, got %+v", asset)
+		}
+	}
+}
+
+func TestQueryAssetsRunsArbitrarySelector(t *testing.T) {
+	contract, ctx, _ := newTestContract("Team1")
+	if err := contract.CreateAsset(ctx, "gem", 10, "Team1"); err != nil {
+		t.Fatalf("CreateAsset failed: %v", err)
+	}
+	contract.Authorizer = &fakeAuthorizer{callerID: "Team2"}
+	if err := contract.CreateAsset(ctx, "gem", 3, "Team2"); err != nil {
+		t.Fatalf("CreateAsset failed: %v", err)
+	}
+
+	result, err := contract.QueryAssets(ctx, `{"selector":{"ID":"gem"}}`, 10, "")
+	if err != nil {
+		t.Fatalf("QueryAssets failed: %v", err)
+	}
+	if len(result.Assets) != 2 {
+		t.Fatalf("expected 2 gem assets, got %d", len(result.Assets))
+	}
+}
@@ -10,6 +10,11 @@ import (
 // SmartContract provides functions for managing an Asset
 type SmartContract struct {
 	contractapi.Contract
+
+	// Authorizer performs the client-identity checks InitLedger and the Distrib-facing
+	// functions rely on. Nil means "use the production cid-backed implementation";
+	// tests may set this to a fake to inject a mock identity.
+	Authorizer Authorizer
 }
 
 // Asset describes basic details of what makes up a simple asset
@@ -21,8 +26,24 @@ type Asset struct {
 	Amount int    `json:"Amount"`
 }
 
-// InitLedger adds a base set of assets to the ledger
+// assetKey returns the single composite key under which the (id, owner) pair
+// is stored. This is the only place that should ever build an Asset's state
+// key so that every read/write path stays in agreement about addressing.
+func assetKey(ctx contractapi.TransactionContextInterface, id string, owner string) (string, error) {
+	compositeKey, err := ctx.GetStub().CreateCompositeKey("Asset", []string{id, owner})
+	if err != nil {
+		return "", fmt.Errorf("failed to create composite key for asset: %v", err)
+	}
+
+	return compositeKey, nil
+}
+
+// InitLedger adds a base set of assets to the ledger. Only the Distrib admin identity may invoke it.
 func (s *SmartContract) InitLedger(ctx contractapi.TransactionContextInterface) error {
+	if err := s.requireDistribAdmin(ctx); err != nil {
+		return err
+	}
+
 	assets := []Asset{
 		{ID: "exp", Amount: 5, Owner: "Distrib"},
 		{ID: "gem", Amount: 3000, Owner: "SEO"},
@@ -38,7 +59,7 @@ func (s *SmartContract) InitLedger(ctx contractapi.TransactionContextInterface)
 			return err
 		}
 
-		compositeKey, err := ctx.GetStub().CreateCompositeKey("Asset", []string{asset.ID, asset.Owner})
+		compositeKey, err := assetKey(ctx, asset.ID, asset.Owner)
 		if err != nil {
 			return err
 		}
@@ -53,13 +74,13 @@ func (s *SmartContract) InitLedger(ctx contractapi.TransactionContextInterface)
 }
 
 // CreateAsset issues a new asset to the world state with given details.
+// Only the identity matching owner may invoke it.
 func (s *SmartContract) CreateAsset(ctx contractapi.TransactionContextInterface, id string, amount int, owner string) error {
-	compositeKey, err := ctx.GetStub().CreateCompositeKey("Asset", []string{id, owner})
-	if err != nil {
-		return fmt.Errorf("failed to create composite key for asset: %v", err)
+	if err := s.requireCaller(ctx, owner); err != nil {
+		return err
 	}
 
-	exists, err := s.AssetExists(ctx, compositeKey)
+	exists, err := s.AssetExists(ctx, id, owner)
 	if err != nil {
 		return err
 	}
@@ -67,6 +88,11 @@ func (s *SmartContract) CreateAsset(ctx contractapi.TransactionContextInterface,
 		return fmt.Errorf("the asset %s already exists", id)
 	}
 
+	compositeKey, err := assetKey(ctx, id, owner)
+	if err != nil {
+		return err
+	}
+
 	asset := Asset{
 		ID:     id,
 		Amount: amount,
@@ -77,21 +103,30 @@ func (s *SmartContract) CreateAsset(ctx contractapi.TransactionContextInterface,
 		return err
 	}
 
-	return ctx.GetStub().PutState(compositeKey, assetJSON)
+	if err := ctx.GetStub().PutState(compositeKey, assetJSON); err != nil {
+		return err
+	}
+
+	return emitEvent(ctx, "AssetCreated", AssetCreated{
+		ID:     asset.ID,
+		Owner:  asset.Owner,
+		Amount: asset.Amount,
+		TxID:   ctx.GetStub().GetTxID(),
+	})
 }
 
 // ReadAsset returns the asset stored in the world state with given id and owner.
 func (s *SmartContract) ReadAsset(ctx contractapi.TransactionContextInterface, id string, owner string) (*Asset, error) {
-	compositeKey, err := ctx.GetStub().CreateCompositeKey("Asset", []string{id, owner})
+	compositeKey, err := assetKey(ctx, id, owner)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create composite key: %v", err)
+		return nil, err
 	}
 	assetJSON, err := ctx.GetStub().GetState(compositeKey)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read from world state: %v", err)
 	}
 	if assetJSON == nil {
-		return nil, fmt.Errorf("the asset %s does not exist", id)
+		return nil, fmt.Errorf("the asset %s owned by %s does not exist", id, owner)
 	}
 
 	var asset Asset
@@ -104,13 +139,23 @@ func (s *SmartContract) ReadAsset(ctx contractapi.TransactionContextInterface, i
 }
 
 // UpdateAsset updates an existing asset in the world state with provided parameters.
+// Only the asset's current owner may invoke it.
 func (s *SmartContract) UpdateAsset(ctx contractapi.TransactionContextInterface, id string, owner string, amount int) error {
-	exists, err := s.AssetExists(ctx, id)
+	if err := s.requireCaller(ctx, owner); err != nil {
+		return err
+	}
+
+	exists, err := s.AssetExists(ctx, id, owner)
 	if err != nil {
 		return err
 	}
 	if !exists {
-		return fmt.Errorf("the asset %s does not exist", id)
+		return fmt.Errorf("the asset %s owned by %s does not exist", id, owner)
+	}
+
+	compositeKey, err := assetKey(ctx, id, owner)
+	if err != nil {
+		return err
 	}
 
 	// overwriting original asset with new asset
@@ -124,12 +169,26 @@ func (s *SmartContract) UpdateAsset(ctx contractapi.TransactionContextInterface,
 		return err
 	}
 
-	return ctx.GetStub().PutState(id, assetJSON)
+	if err := ctx.GetStub().PutState(compositeKey, assetJSON); err != nil {
+		return err
+	}
+
+	return emitEvent(ctx, "AssetUpdated", AssetUpdated{
+		ID:     asset.ID,
+		Owner:  asset.Owner,
+		Amount: asset.Amount,
+		TxID:   ctx.GetStub().GetTxID(),
+	})
 }
 
-// AssetExists returns true when asset with given ID exists in world state
-func (s *SmartContract) AssetExists(ctx contractapi.TransactionContextInterface, id string) (bool, error) {
-	assetJSON, err := ctx.GetStub().GetState(id)
+// AssetExists returns true when an asset with the given id and owner exists in world state
+func (s *SmartContract) AssetExists(ctx contractapi.TransactionContextInterface, id string, owner string) (bool, error) {
+	compositeKey, err := assetKey(ctx, id, owner)
+	if err != nil {
+		return false, err
+	}
+
+	assetJSON, err := ctx.GetStub().GetState(compositeKey)
 	if err != nil {
 		return false, fmt.Errorf("failed to read from world state: %v", err)
 	}
@@ -137,39 +196,151 @@ func (s *SmartContract) AssetExists(ctx contractapi.TransactionContextInterface,
 	return assetJSON != nil, nil
 }
 
-// TransferAsset updates the owner field of asset with given id in world state, and returns the old owner.
-func (s *SmartContract) TransferAsset(ctx contractapi.TransactionContextInterface, id string, newOwner string, Amount int) (string, error) {
-	asset, err := s.ReadAsset(ctx, id)
+// TransferAsset moves amount units of asset id from oldOwner's record to newOwner's record,
+// creating newOwner's record if it does not yet exist, and returns the newOwner's resulting balance.
+// Only oldOwner's own identity may invoke it.
+func (s *SmartContract) TransferAsset(ctx contractapi.TransactionContextInterface, id string, oldOwner string, newOwner string, amount int) (int, error) {
+	if err := s.requireCaller(ctx, oldOwner); err != nil {
+		return 0, err
+	}
+	if amount <= 0 {
+		return 0, fmt.Errorf("amount must be positive")
+	}
+
+	source, err := s.ReadAsset(ctx, id, oldOwner)
 	if err != nil {
-		return "", err
+		return 0, err
+	}
+	if source.Amount < amount {
+		return 0, fmt.Errorf("%s does not have enough %s to transfer", oldOwner, id)
 	}
 
-	oldOwner := asset.Owner
-	asset.Owner = newOwner
+	dest, err := s.ReadAsset(ctx, id, newOwner)
+	if err != nil {
+		dest = &Asset{ID: id, Owner: newOwner, Amount: 0}
+	}
 
-	assetJSON, err := json.Marshal(asset)
+	source.Amount -= amount
+	dest.Amount += amount
+
+	sourceKey, err := assetKey(ctx, id, oldOwner)
+	if err != nil {
+		return 0, err
+	}
+	sourceJSON, err := json.Marshal(source)
 	if err != nil {
-		return "", err
+		return 0, err
+	}
+	if err := ctx.GetStub().PutState(sourceKey, sourceJSON); err != nil {
+		return 0, fmt.Errorf("failed to update %s's asset: %v", oldOwner, err)
 	}
 
-	err = ctx.GetStub().PutState(id, assetJSON)
+	destKey, err := assetKey(ctx, id, newOwner)
+	if err != nil {
+		return 0, err
+	}
+	destJSON, err := json.Marshal(dest)
 	if err != nil {
-		return "", err
+		return 0, err
+	}
+	if err := ctx.GetStub().PutState(destKey, destJSON); err != nil {
+		return 0, fmt.Errorf("failed to update %s's asset: %v", newOwner, err)
+	}
+
+	if err := emitEvent(ctx, "AssetTransferred", AssetTransferred{
+		ID:       id,
+		OldOwner: oldOwner,
+		NewOwner: newOwner,
+		Amount:   amount,
+		TxID:     ctx.GetStub().GetTxID(),
+	}); err != nil {
+		return 0, err
 	}
 
-	return oldOwner, nil
+	return dest.Amount, nil
 }
 
-// TransferGemToDistrib transfers Gem from a user to Distrib and credits Exp to the user's wallet
+// MigrateLegacyAssets rewrites any asset that was stored under a plain id key
+// (predating the composite-key addressing scheme) into its (id, owner)
+// composite-key form, and removes the legacy entry. It is safe to invoke
+// repeatedly: once a ledger has no legacy entries left it is a no-op.
+func (s *SmartContract) MigrateLegacyAssets(ctx contractapi.TransactionContextInterface) error {
+	resultsIterator, err := ctx.GetStub().GetStateByRange("", "")
+	if err != nil {
+		return fmt.Errorf("failed to range over world state: %v", err)
+	}
+	defer resultsIterator.Close()
+
+	var legacyKeys []string
+	var legacyAssets []Asset
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return fmt.Errorf("failed to iterate over world state: %v", err)
+		}
+
+		// Composite keys decode cleanly via SplitCompositeKey; a legacy entry,
+		// stored under the plain asset id, does not, so SplitCompositeKey
+		// returns an error for it. This is the only reliable way to tell the
+		// two apart: GetStateByRange("", "") returns composite-keyed entries
+		// too, and the entry's JSON payload looks the same either way.
+		if _, _, err := ctx.GetStub().SplitCompositeKey(queryResponse.Key); err == nil {
+			continue
+		}
+
+		var asset Asset
+		if err := json.Unmarshal(queryResponse.Value, &asset); err != nil {
+			continue
+		}
+		if asset.ID == "" || asset.Owner == "" {
+			continue
+		}
+
+		legacyKeys = append(legacyKeys, queryResponse.Key)
+		legacyAssets = append(legacyAssets, asset)
+	}
+
+	for i, asset := range legacyAssets {
+		compositeKey, err := assetKey(ctx, asset.ID, asset.Owner)
+		if err != nil {
+			return err
+		}
+
+		assetJSON, err := json.Marshal(asset)
+		if err != nil {
+			return err
+		}
+
+		if compositeKey == legacyKeys[i] {
+			// Already in composite form under this exact key; nothing to migrate.
+			continue
+		}
+
+		if err := ctx.GetStub().PutState(compositeKey, assetJSON); err != nil {
+			return fmt.Errorf("failed to migrate asset %s/%s: %v", asset.ID, asset.Owner, err)
+		}
+		if err := ctx.GetStub().DelState(legacyKeys[i]); err != nil {
+			return fmt.Errorf("failed to remove legacy entry for asset %s/%s: %v", asset.ID, asset.Owner, err)
+		}
+	}
+
+	return nil
+}
+
+// TransferGemToDistrib transfers Gem from a user to Distrib and credits Exp to the user's wallet.
+// The invoking identity must be the user itself.
 func (s *SmartContract) TransferGemToDistrib(ctx contractapi.TransactionContextInterface, user string, gemAmount int) error {
+	if err := s.requireCaller(ctx, user); err != nil {
+		return err
+	}
 	if gemAmount <= 0 {
 		return fmt.Errorf("gemAmount must be positive")
 	}
 
 	// Get the user's Gem asset
-	userGemKey, err := ctx.GetStub().CreateCompositeKey("Asset", []string{"gem", user})
+	userGemKey, err := assetKey(ctx, "gem", user)
 	if err != nil {
-		return fmt.Errorf("failed to create composite key for user's Gem asset: %v", err)
+		return err
 	}
 	userGemJSON, err := ctx.GetStub().GetState(userGemKey)
 	if err != nil {
@@ -190,10 +361,31 @@ func (s *SmartContract) TransferGemToDistrib(ctx contractapi.TransactionContextI
 		return fmt.Errorf("insufficient Gem balance")
 	}
 
+	// Compute how much Exp this Gem mints under the current policy, minus Distrib's fee,
+	// before touching any state, so an amount too small to mint anything is rejected cleanly.
+	policy, err := s.GetPolicy(ctx)
+	if err != nil {
+		return err
+	}
+	grossExp := gemAmount * policy.GemPerExpNumerator / policy.GemPerExpDenominator
+	if grossExp <= 0 {
+		return fmt.Errorf("gemAmount %d is too small to mint any Exp under the current policy", gemAmount)
+	}
+	fee := grossExp * policy.DistribFeeBps / 10000
+	netExp := grossExp - fee
+
+	minted, err := totalExpMinted(ctx)
+	if err != nil {
+		return err
+	}
+	if policy.MaxExpSupply > 0 && minted+grossExp > policy.MaxExpSupply {
+		return fmt.Errorf("minting %d Exp would exceed the maximum supply of %d", grossExp, policy.MaxExpSupply)
+	}
+
 	// Get Distrib's Gem asset
-	distribGemKey, err := ctx.GetStub().CreateCompositeKey("Asset", []string{"gem", "Distrib"})
+	distribGemKey, err := assetKey(ctx, "gem", "Distrib")
 	if err != nil {
-		return fmt.Errorf("failed to create composite key for Distrib's Gem asset: %v", err)
+		return err
 	}
 	distribGemJSON, err := ctx.GetStub().GetState(distribGemKey)
 	if err != nil {
@@ -234,9 +426,9 @@ func (s *SmartContract) TransferGemToDistrib(ctx contractapi.TransactionContextI
 	}
 
 	// Get the user's Exp asset
-	userExpKey, err := ctx.GetStub().CreateCompositeKey("Asset", []string{"exp", user})
+	userExpKey, err := assetKey(ctx, "exp", user)
 	if err != nil {
-		return fmt.Errorf("failed to create composite key for user's Exp asset: %v", err)
+		return err
 	}
 	userExpJSON, err := ctx.GetStub().GetState(userExpKey)
 	if err != nil {
@@ -252,8 +444,8 @@ func (s *SmartContract) TransferGemToDistrib(ctx contractapi.TransactionContextI
 		userExp = Asset{ID: "exp", Owner: user, Amount: 0}
 	}
 
-	// Credit Exp to the user's wallet
-	userExp.Amount += gemAmount
+	// Credit the net Exp to the user's wallet
+	userExp.Amount += netExp
 
 	// Update the user's Exp asset
 	userExpJSON, err = json.Marshal(userExp)
@@ -265,32 +457,26 @@ func (s *SmartContract) TransferGemToDistrib(ctx contractapi.TransactionContextI
 		return fmt.Errorf("failed to update user's Exp asset: %v", err)
 	}
 
-	return nil
-}
-
-// GetAllAssets returns all assets found in the world state
-func (s *SmartContract) GetAllAssets(ctx contractapi.TransactionContextInterface) ([]Asset, error) {
-	// Get all assets from the ledger
-	resultsIterator, err := ctx.GetStub().GetStateByPartialCompositeKey("Asset", []string{})
-	if err != nil {
-		return nil, fmt.Errorf("failed to get assets: %v", err)
+	if fee > 0 {
+		if err := creditFees(ctx, fee); err != nil {
+			return err
+		}
 	}
-	defer resultsIterator.Close()
 
-	var assets []Asset
-	for resultsIterator.HasNext() {
-		queryResponse, err := resultsIterator.Next()
-		if err != nil {
-			return nil, fmt.Errorf("failed to iterate over assets: %v", err)
-		}
+	if err := putTotalExpMinted(ctx, minted+grossExp); err != nil {
+		return err
+	}
 
-		var asset Asset
-		err = json.Unmarshal(queryResponse.Value, &asset)
-		if err != nil {
-			return nil, fmt.Errorf("failed to unmarshal asset: %v", err)
-		}
-		assets = append(assets, asset)
+	txTimestamp, err := ctx.GetStub().GetTxTimestamp()
+	if err != nil {
+		return fmt.Errorf("failed to read transaction timestamp: %v", err)
 	}
 
-	return assets, nil
+	return emitEvent(ctx, "GemExchangedForExp", GemExchangedForExp{
+		User:        user,
+		GemAmount:   gemAmount,
+		ExpCredited: netExp,
+		TxID:        ctx.GetStub().GetTxID(),
+		Timestamp:   txTimestamp.Seconds,
+	})
 }
@@ -0,0 +1,205 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func newTestContract(callerID string) (*SmartContract, *fakeTransactionContext, *fakeStub) {
+	ctx, stub := newFakeContext()
+	contract := &SmartContract{Authorizer: &fakeAuthorizer{callerID: callerID, isAdmin: true}}
+	return contract, ctx, stub
+}
+
+func TestCreateAndReadAsset(t *testing.T) {
+	contract, ctx, stub := newTestContract("Team1")
+
+	if err := contract.CreateAsset(ctx, "gem", 10, "Team1"); err != nil {
+		t.Fatalf("CreateAsset failed: %v", err)
+	}
+
+	asset, err := contract.ReadAsset(ctx, "gem", "Team1")
+	if err != nil {
+		t.Fatalf("ReadAsset failed: %v", err)
+	}
+	if asset.Amount != 10 {
+		t.Fatalf("expected amount 10, got %d", asset.Amount)
+	}
+
+	if stub.lastEvent != "AssetCreated" {
+		t.Fatalf("expected an AssetCreated event, got %q", stub.lastEvent)
+	}
+	var payload AssetCreated
+	if err := json.Unmarshal(stub.lastPayload, &payload); err != nil {
+		t.Fatalf("failed to unmarshal AssetCreated payload: %v", err)
+	}
+	if payload.ID != "gem" || payload.Owner != "Team1" || payload.Amount != 10 {
+		t.Fatalf("unexpected AssetCreated payload: %+v", payload)
+	}
+}
+
+func TestCreateAssetRejectsDuplicate(t *testing.T) {
+	contract, ctx, _ := newTestContract("Team1")
+
+	if err := contract.CreateAsset(ctx, "gem", 10, "Team1"); err != nil {
+		t.Fatalf("CreateAsset failed: %v", err)
+	}
+	if err := contract.CreateAsset(ctx, "gem", 5, "Team1"); err == nil {
+		t.Fatal("expected CreateAsset to reject a duplicate (id, owner) pair")
+	}
+}
+
+func TestUpdateAssetOnlyTouchesItsOwnCompositeKey(t *testing.T) {
+	contract, ctx, stub := newTestContract("Team1")
+	if err := contract.CreateAsset(ctx, "gem", 10, "Team1"); err != nil {
+		t.Fatalf("CreateAsset failed: %v", err)
+	}
+
+	if err := contract.UpdateAsset(ctx, "gem", "Team1", 42); err != nil {
+		t.Fatalf("UpdateAsset failed: %v", err)
+	}
+
+	asset, err := contract.ReadAsset(ctx, "gem", "Team1")
+	if err != nil {
+		t.Fatalf("ReadAsset failed: %v", err)
+	}
+	if asset.Amount != 42 {
+		t.Fatalf("expected amount 42 after update, got %d", asset.Amount)
+	}
+
+	// Before the composite-key unification, UpdateAsset wrote to the raw id
+	// instead of the (id, owner) composite key.
+	if _, ok := stub.state["gem"]; ok {
+		t.Fatal("UpdateAsset must not write to the plain, non-composite id key")
+	}
+
+	if stub.lastEvent != "AssetUpdated" {
+		t.Fatalf("expected an AssetUpdated event, got %q", stub.lastEvent)
+	}
+	var payload AssetUpdated
+	if err := json.Unmarshal(stub.lastPayload, &payload); err != nil {
+		t.Fatalf("failed to unmarshal AssetUpdated payload: %v", err)
+	}
+	if payload.Amount != 42 {
+		t.Fatalf("expected AssetUpdated payload to report amount 42, got %d", payload.Amount)
+	}
+}
+
+func TestAssetExistsDistinguishesOwners(t *testing.T) {
+	contract, ctx, _ := newTestContract("Team1")
+	if err := contract.CreateAsset(ctx, "gem", 10, "Team1"); err != nil {
+		t.Fatalf("CreateAsset failed: %v", err)
+	}
+
+	exists, err := contract.AssetExists(ctx, "gem", "Team1")
+	if err != nil || !exists {
+		t.Fatalf("expected gem/Team1 to exist, got exists=%v err=%v", exists, err)
+	}
+
+	exists, err = contract.AssetExists(ctx, "gem", "Team2")
+	if err != nil || exists {
+		t.Fatalf("expected gem/Team2 not to exist, got exists=%v err=%v", exists, err)
+	}
+}
+
+func TestTransferAssetMovesAmountBetweenOwners(t *testing.T) {
+	contract, ctx, stub := newTestContract("Team1")
+	if err := contract.CreateAsset(ctx, "gem", 10, "Team1"); err != nil {
+		t.Fatalf("CreateAsset failed: %v", err)
+	}
+
+	newBalance, err := contract.TransferAsset(ctx, "gem", "Team1", "Team2", 4)
+	if err != nil {
+		t.Fatalf("TransferAsset failed: %v", err)
+	}
+	if newBalance != 4 {
+		t.Fatalf("expected newOwner balance 4, got %d", newBalance)
+	}
+
+	source, err := contract.ReadAsset(ctx, "gem", "Team1")
+	if err != nil {
+		t.Fatalf("ReadAsset(source) failed: %v", err)
+	}
+	if source.Amount != 6 {
+		t.Fatalf("expected source balance 6 after transfer, got %d", source.Amount)
+	}
+
+	if stub.lastEvent != "AssetTransferred" {
+		t.Fatalf("expected an AssetTransferred event, got %q", stub.lastEvent)
+	}
+	var payload AssetTransferred
+	if err := json.Unmarshal(stub.lastPayload, &payload); err != nil {
+		t.Fatalf("failed to unmarshal AssetTransferred payload: %v", err)
+	}
+	if payload.OldOwner != "Team1" || payload.NewOwner != "Team2" || payload.Amount != 4 {
+		t.Fatalf("unexpected AssetTransferred payload: %+v", payload)
+	}
+}
+
+func TestTransferAssetRejectsInsufficientBalance(t *testing.T) {
+	contract, ctx, _ := newTestContract("Team1")
+	if err := contract.CreateAsset(ctx, "gem", 3, "Team1"); err != nil {
+		t.Fatalf("CreateAsset failed: %v", err)
+	}
+
+	if _, err := contract.TransferAsset(ctx, "gem", "Team1", "Team2", 4); err == nil {
+		t.Fatal("expected TransferAsset to reject a transfer larger than the source balance")
+	}
+}
+
+func TestMigrateLegacyAssetsRewritesOnlyLegacyEntries(t *testing.T) {
+	contract, ctx, stub := newTestContract("Team1")
+
+	// Simulate a pre-migration ledger entry stored under the plain asset id.
+	legacy, _ := json.Marshal(Asset{ID: "gem", Owner: "Team1", Amount: 7})
+	stub.state["gem"] = legacy
+
+	// And a normal, already-composite-keyed entry that must be left untouched.
+	if err := contract.CreateAsset(ctx, "exp", 5, "Team1"); err != nil {
+		t.Fatalf("CreateAsset failed: %v", err)
+	}
+	compositeKey, _ := stub.CreateCompositeKey("Asset", []string{"exp", "Team1"})
+	before := string(stub.state[compositeKey])
+
+	if err := contract.MigrateLegacyAssets(ctx); err != nil {
+		t.Fatalf("MigrateLegacyAssets failed: %v", err)
+	}
+
+	if _, ok := stub.state["gem"]; ok {
+		t.Fatal("expected the legacy plain-key entry to be removed")
+	}
+
+	migrated, err := contract.ReadAsset(ctx, "gem", "Team1")
+	if err != nil {
+		t.Fatalf("expected migrated asset to be readable via composite key: %v", err)
+	}
+	if migrated.Amount != 7 {
+		t.Fatalf("expected migrated amount 7, got %d", migrated.Amount)
+	}
+
+	if string(stub.state[compositeKey]) != before {
+		t.Fatal("MigrateLegacyAssets must not touch an already-composite-keyed asset")
+	}
+}
+
+func TestMigrateLegacyAssetsIsIdempotent(t *testing.T) {
+	contract, ctx, stub := newTestContract("Team1")
+
+	legacy, _ := json.Marshal(Asset{ID: "gem", Owner: "Team1", Amount: 7})
+	stub.state["gem"] = legacy
+
+	if err := contract.MigrateLegacyAssets(ctx); err != nil {
+		t.Fatalf("first MigrateLegacyAssets failed: %v", err)
+	}
+	if err := contract.MigrateLegacyAssets(ctx); err != nil {
+		t.Fatalf("second MigrateLegacyAssets failed: %v", err)
+	}
+
+	migrated, err := contract.ReadAsset(ctx, "gem", "Team1")
+	if err != nil {
+		t.Fatalf("expected migrated asset to survive a second migration pass: %v", err)
+	}
+	if migrated.Amount != 7 {
+		t.Fatalf("expected amount to remain 7, got %d", migrated.Amount)
+	}
+}